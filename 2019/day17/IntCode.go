@@ -41,9 +41,10 @@ type IntCodeMachine struct {
 	stopped      bool
 	debug        bool
 	relativeBase int
-	memory       []int
+	memory       Memory
 	inputBuffer  []int
 	outputBuffer []int
+	jit          *jitCache
 }
 
 // Max - Return max of two int values
@@ -55,15 +56,48 @@ func Max(a, b int) int {
 	return b
 }
 
-// Init - Initialize machine with a program and input buffer
+// Init - Initialize machine with a program and input buffer, backed by a dense
+// slice of memory.
 func (m *IntCodeMachine) Init(program []int, input []int) {
 	m.pc = 0
 	m.stopped = false
 	m.debug = false
 	m.relativeBase = 0
-	m.memory = make([]int, Max(len(program), minMemorySize))
-	copy(m.memory[:], program)
+	m.memory = newSliceMemory(program)
 	m.inputBuffer = input
+	m.jit = nil
+}
+
+// InitPaged - Like Init, but backs memory with a lazily-allocated, page-based
+// Memory implementation instead of a single dense slice, so that Clone is
+// cheap for puzzles that fork the machine many times (Day 15's maze BFS, Day
+// 17's scaffold search).
+func (m *IntCodeMachine) InitPaged(program []int, input []int) {
+	m.pc = 0
+	m.stopped = false
+	m.debug = false
+	m.relativeBase = 0
+	m.memory = newPageMemory(program)
+	m.inputBuffer = input
+	m.jit = nil
+}
+
+// Clone - Produce an independent machine starting from the same state as m.
+// For a machine set up with InitPaged, memory pages are shared copy-on-write
+// with m until either side writes to one; a machine set up with Init gets a
+// full copy of its dense memory.
+func (m *IntCodeMachine) Clone() *IntCodeMachine {
+	clone := &IntCodeMachine{
+		pc:           m.pc,
+		stopped:      m.stopped,
+		debug:        m.debug,
+		relativeBase: m.relativeBase,
+		memory:       m.memory.Clone(),
+		inputBuffer:  append([]int(nil), m.inputBuffer...),
+		outputBuffer: append([]int(nil), m.outputBuffer...),
+	}
+
+	return clone
 }
 
 func getParameterMode(op int, param int) int {
@@ -121,29 +155,34 @@ func getOpName(op int) string {
 }
 
 func (m *IntCodeMachine) writeParameter(param int, offs int, value int) {
-	op := m.memory[offs]
+	op := m.memory.Read(offs)
 	mode := getParameterMode(op, param)
-	addr := m.memory[offs+param+1]
+	addr := m.memory.Read(offs + param + 1)
 
 	switch mode {
 	case 0:
-		m.memory[addr] = value
+		m.memory.Write(addr, value)
 	case 2:
-		m.memory[m.relativeBase+addr] = value
+		addr += m.relativeBase
+		m.memory.Write(addr, value)
 	default:
 		log.Fatal("Illegal mode for write parameter")
 	}
+
+	if m.jit != nil {
+		m.jit.invalidate(addr)
+	}
 }
 
 func (m *IntCodeMachine) readParameter(param int, offs int) (val int) {
-	op := m.memory[offs]
+	op := m.memory.Read(offs)
 	mode := getParameterMode(op, param)
-	addr := m.memory[offs+param+1]
+	addr := m.memory.Read(offs + param + 1)
 
 	switch mode {
 	case 0:
 		// Position mode
-		val = m.memory[addr]
+		val = m.memory.Read(addr)
 
 	case 1:
 		// Immediate mode
@@ -151,7 +190,7 @@ func (m *IntCodeMachine) readParameter(param int, offs int) (val int) {
 
 	case 2:
 		// Relative mode
-		val = m.memory[m.relativeBase+addr]
+		val = m.memory.Read(m.relativeBase + addr)
 
 	default:
 		log.Fatal("Illegal mode")
@@ -171,13 +210,76 @@ func (m *IntCodeMachine) Reset() {
 	m.outputBuffer = []int{}
 }
 
+// execStep - Execute the arithmetic, comparison, and jump opcodes shared
+// verbatim by Run, RunAsync, and step: OpInput/OpOutput/OpHalt are the only
+// opcodes whose handling differs between them (buffers vs. channels vs.
+// single-step), so those stay in each caller's own switch and everything else
+// is decoded here. Returns false for an opcode it doesn't recognize, leaving
+// error formatting to the caller.
+func execStep(m *IntCodeMachine, op int, pc *int) bool {
+	switch op % 100 {
+	case OpAdd:
+		op1 := m.readParameter(0, *pc)
+		op2 := m.readParameter(1, *pc)
+		m.writeParameter(2, *pc, op1+op2)
+		*pc += 4
+	case OpMul:
+		op1 := m.readParameter(0, *pc)
+		op2 := m.readParameter(1, *pc)
+		m.writeParameter(2, *pc, op1*op2)
+		*pc += 4
+	case OpJumpNotZero:
+		op1 := m.readParameter(0, *pc)
+		op2 := m.readParameter(1, *pc)
+		if op1 != 0 {
+			*pc = op2
+		} else {
+			*pc += 3
+		}
+	case OpJumpZero:
+		op1 := m.readParameter(0, *pc)
+		op2 := m.readParameter(1, *pc)
+		if op1 == 0 {
+			*pc = op2
+		} else {
+			*pc += 3
+		}
+	case OpLess:
+		op1 := m.readParameter(0, *pc)
+		op2 := m.readParameter(1, *pc)
+		if op1 < op2 {
+			m.writeParameter(2, *pc, 1)
+		} else {
+			m.writeParameter(2, *pc, 0)
+		}
+		*pc += 4
+	case OpEqual:
+		op1 := m.readParameter(0, *pc)
+		op2 := m.readParameter(1, *pc)
+		if op1 == op2 {
+			m.writeParameter(2, *pc, 1)
+		} else {
+			m.writeParameter(2, *pc, 0)
+		}
+		*pc += 4
+	case OpAddToRelativeBase:
+		op1 := m.readParameter(0, *pc)
+		m.relativeBase += op1
+		*pc += 2
+	default:
+		return false
+	}
+
+	return true
+}
+
 // Run - Run machine until stopped or blocked
 func (m *IntCodeMachine) Run(input []int) (err error) {
 	m.inputBuffer = append(m.inputBuffer, input...)
 	pc := &m.pc
 
 	for {
-		op := m.memory[*pc]
+		op := m.memory.Read(*pc)
 
 		if m.debug {
 			fmt.Printf("Op %d: %s. Rel base: %d\n", op, getOpName(op), m.relativeBase)
@@ -191,16 +293,6 @@ func (m *IntCodeMachine) Run(input []int) (err error) {
 		// fmt.Printf("Next: %d (@%d)\n", buf[pc], pc)
 
 		switch op % 100 {
-		case OpAdd:
-			op1 := m.readParameter(0, *pc)
-			op2 := m.readParameter(1, *pc)
-			m.writeParameter(2, *pc, op1+op2)
-			*pc += 4
-		case OpMul:
-			op1 := m.readParameter(0, *pc)
-			op2 := m.readParameter(1, *pc)
-			m.writeParameter(2, *pc, op1*op2)
-			*pc += 4
 		case OpInput:
 			if len(m.inputBuffer) > 0 {
 				var val int
@@ -215,49 +307,13 @@ func (m *IntCodeMachine) Run(input []int) (err error) {
 			op1 := m.readParameter(0, *pc)
 			m.outputBuffer = append(m.outputBuffer, op1)
 			*pc += 2
-		case OpJumpNotZero:
-			op1 := m.readParameter(0, *pc)
-			op2 := m.readParameter(1, *pc)
-			if op1 != 0 {
-				*pc = op2
-			} else {
-				*pc += 3
-			}
-		case OpJumpZero:
-			op1 := m.readParameter(0, *pc)
-			op2 := m.readParameter(1, *pc)
-			if op1 == 0 {
-				*pc = op2
-			} else {
-				*pc += 3
-			}
-		case OpLess:
-			op1 := m.readParameter(0, *pc)
-			op2 := m.readParameter(1, *pc)
-			if op1 < op2 {
-				m.writeParameter(2, *pc, 1)
-			} else {
-				m.writeParameter(2, *pc, 0)
-			}
-			*pc += 4
-		case OpEqual:
-			op1 := m.readParameter(0, *pc)
-			op2 := m.readParameter(1, *pc)
-			if op1 == op2 {
-				m.writeParameter(2, *pc, 1)
-			} else {
-				m.writeParameter(2, *pc, 0)
-			}
-			*pc += 4
-		case OpAddToRelativeBase:
-			op1 := m.readParameter(0, *pc)
-			m.relativeBase += op1
-			*pc += 2
 		default:
-			return fmt.Errorf("Invalid operator '%d' at index %d", m.memory[*pc], *pc)
+			if !execStep(m, op, pc) {
+				return fmt.Errorf("Invalid operator '%d' at index %d", op, *pc)
+			}
 		}
 
-		if *pc >= len(m.memory) {
+		if *pc >= m.memory.Len() {
 			return fmt.Errorf("Reached end of code")
 		}
 	}
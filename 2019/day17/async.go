@@ -0,0 +1,64 @@
+package main
+
+import (
+	"context"
+	"fmt"
+)
+
+// RunAsync - Run the machine to completion using channels instead of Run's
+// buffer-based protocol. Intended to be started with `go machine.RunAsync(ctx,
+// in, out)` so several machines can be wired together with ordinary channels.
+// Blocks on in for OpInput and sends on out for OpOutput; closes out and
+// returns when the machine halts, ctx is cancelled, or an invalid opcode is
+// hit.
+func (m *IntCodeMachine) RunAsync(ctx context.Context, in <-chan int, out chan<- int) error {
+	defer close(out)
+
+	pc := &m.pc
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		default:
+		}
+
+		op := m.memory.Read(*pc)
+
+		if m.debug {
+			fmt.Printf("Op %d: %s. Rel base: %d\n", op, getOpName(op), m.relativeBase)
+		}
+
+		if op == OpHalt {
+			m.stopped = true
+			return nil
+		}
+
+		switch op % 100 {
+		case OpInput:
+			select {
+			case val := <-in:
+				m.writeParameter(0, *pc, val)
+				*pc += 2
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		case OpOutput:
+			op1 := m.readParameter(0, *pc)
+			select {
+			case out <- op1:
+				*pc += 2
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		default:
+			if !execStep(m, op, pc) {
+				return fmt.Errorf("Invalid operator '%d' at index %d", op, *pc)
+			}
+		}
+
+		if *pc >= m.memory.Len() {
+			return fmt.Errorf("Reached end of code")
+		}
+	}
+}
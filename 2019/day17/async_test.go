@@ -0,0 +1,61 @@
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// TestRunAsyncEchoesInputToOutput exercises the basic channel-based
+// input/output round trip: a value sent on in should come back out on out
+// unchanged, the same as Run's buffer-based protocol would produce.
+func TestRunAsyncEchoesInputToOutput(t *testing.T) {
+	var m IntCodeMachine
+	m.Init([]int{3, 7, 4, 7, 99, 0, 0, 0}, nil) // INP -> mem[7], OUT mem[7], HALT
+
+	in := make(chan int, 1)
+	out := make(chan int, 1)
+	in <- 42
+
+	if err := m.RunAsync(context.Background(), in, out); err != nil {
+		t.Fatalf("RunAsync: %v", err)
+	}
+
+	select {
+	case got := <-out:
+		if got != 42 {
+			t.Fatalf("out = %d, want 42", got)
+		}
+	default:
+		t.Fatal("no value received on out")
+	}
+}
+
+// TestRunAsyncStopsOnContextCancellation guards against RunAsync hanging
+// forever on a blocked channel op: a machine parked on OpInput with nothing
+// to read must return ctx.Err() promptly once ctx is cancelled, instead of
+// blocking on the channel read forever.
+func TestRunAsyncStopsOnContextCancellation(t *testing.T) {
+	var m IntCodeMachine
+	m.Init([]int{3, 0, 99}, nil) // INP -> mem[0], blocks forever with nothing sent on in
+
+	ctx, cancel := context.WithCancel(context.Background())
+	in := make(chan int)
+	out := make(chan int)
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- m.RunAsync(ctx, in, out)
+	}()
+
+	cancel()
+
+	select {
+	case err := <-errCh:
+		if err != context.Canceled {
+			t.Fatalf("RunAsync error = %v, want context.Canceled", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("RunAsync did not return after context cancellation")
+	}
+}
@@ -0,0 +1,195 @@
+package main
+
+import (
+	"fmt"
+	"io"
+)
+
+// Breakpoint - A PC address execution should stop at when running under
+// Debugger.Continue.
+type Breakpoint struct {
+	PC      int
+	Enabled bool
+}
+
+// Frame - An approximation of a call frame: Step pushes one on a relative-base
+// increase (call-like) and pops one on a decrease (return-like).
+type Frame struct {
+	PC           int
+	RelativeBase int
+}
+
+// Debugger - A stepping controller wrapped around an IntCodeMachine:
+// breakpoints, single-stepping, watch addresses, and a best-effort call stack.
+type Debugger struct {
+	machine     *IntCodeMachine
+	breakpoints map[int]*Breakpoint
+	watches     []int
+	frames      []Frame
+	parkedAt    int // pc Continue last stopped at because of a breakpoint, or -1
+}
+
+// Debugger - Attach a Debugger controller to the machine.
+func (m *IntCodeMachine) Debugger() *Debugger {
+	return &Debugger{
+		machine:     m,
+		breakpoints: map[int]*Breakpoint{},
+		parkedAt:    -1,
+	}
+}
+
+// SetBreakpoint - Stop Continue when the machine's PC reaches pc.
+func (d *Debugger) SetBreakpoint(pc int) {
+	d.breakpoints[pc] = &Breakpoint{PC: pc, Enabled: true}
+}
+
+// ClearBreakpoint - Remove a previously set breakpoint.
+func (d *Debugger) ClearBreakpoint(pc int) {
+	delete(d.breakpoints, pc)
+}
+
+// Watch - Add addr to the set of memory addresses reported by Watches.
+func (d *Debugger) Watch(addr int) {
+	d.watches = append(d.watches, addr)
+}
+
+// Watches - Current value of every watched address.
+func (d *Debugger) Watches() map[int]int {
+	values := map[int]int{}
+	for _, addr := range d.watches {
+		values[addr] = d.machine.memory.Read(addr)
+	}
+	return values
+}
+
+// Step - Execute exactly one instruction. An OpAddToRelativeBase that raises
+// the relative base pushes a Frame (call-like); one that lowers it pops the
+// innermost Frame, if any (return-like).
+func (d *Debugger) Step() error {
+	m := d.machine
+	pc := m.pc
+	beforeBase := m.relativeBase
+
+	if err := m.step(); err != nil {
+		return err
+	}
+
+	switch {
+	case m.relativeBase > beforeBase:
+		d.frames = append(d.frames, Frame{PC: pc, RelativeBase: m.relativeBase})
+	case m.relativeBase < beforeBase && len(d.frames) > 0:
+		d.frames = d.frames[:len(d.frames)-1]
+	}
+
+	return nil
+}
+
+// Continue - Step repeatedly until an enabled breakpoint is hit, the machine
+// halts, or it blocks on input. The pc Continue last stopped at is ignored for
+// one step, so a second call advances past the breakpoint it just hit instead
+// of re-triggering it forever.
+func (d *Debugger) Continue() error {
+	for {
+		if d.machine.stopped {
+			return nil
+		}
+
+		if bp, ok := d.breakpoints[d.machine.pc]; ok && bp.Enabled && d.machine.pc != d.parkedAt {
+			d.parkedAt = d.machine.pc
+			return nil
+		}
+		d.parkedAt = -1
+
+		pcBefore := d.machine.pc
+		if err := d.Step(); err != nil {
+			return err
+		}
+
+		// step() leaves pc unchanged when blocked on input; stop instead of
+		// spinning forever.
+		if d.machine.pc == pcBefore && !d.machine.stopped {
+			return nil
+		}
+	}
+}
+
+// Backtrace - The current call stack, outermost first. An approximation:
+// IntCode has no return addresses, and nesting only tracks correctly for code
+// that balances its relative-base increases and decreases.
+func (d *Debugger) Backtrace() []Frame {
+	return d.frames
+}
+
+// PrintState - Print a plain-text status view: disassembly around the current
+// PC (up to eight listing entries on either side), the input/output buffers,
+// the relative base, and any watches.
+//
+// NOT DONE: the request asked for an interactive TUI (termbox or bubbletea)
+// built around this same information. That part is incomplete, not merged -
+// this module has no termbox/bubbletea dependency yet, and wiring one up is
+// its own follow-up request; PrintState is a one-shot text dump, not the
+// interactive front-end that was asked for.
+func (d *Debugger) PrintState(w io.Writer) {
+	m := d.machine
+
+	const around = 8 // listing entries shown on either side of the current one
+	listing, err := Disassemble(m.memory.Snapshot())
+	if err == nil {
+		cur := 0
+		for i, ins := range listing {
+			if ins.Offset <= m.pc {
+				cur = i
+			}
+		}
+		from, to := Max(cur-around, 0), cur+around+1
+		if to > len(listing) {
+			to = len(listing)
+		}
+		for _, ins := range listing[from:to] {
+			marker := "  "
+			if ins.Offset == m.pc {
+				marker = "->"
+			}
+			fmt.Fprintf(w, "%s %5d: %s\n", marker, ins.Offset, ins.String())
+		}
+	}
+
+	fmt.Fprintf(w, "rb=%d input=%v output=%v watches=%v\n", m.relativeBase, m.inputBuffer, m.outputBuffer, d.Watches())
+}
+
+// step - Execute exactly one instruction. Returns nil without advancing pc if
+// the machine is blocked on input, matching Run's blocking semantics.
+func (m *IntCodeMachine) step() error {
+	pc := &m.pc
+	op := m.memory.Read(*pc)
+
+	if op == OpHalt {
+		m.stopped = true
+		return nil
+	}
+
+	switch op % 100 {
+	case OpInput:
+		if len(m.inputBuffer) == 0 {
+			return nil
+		}
+		var val int
+		val, m.inputBuffer = m.inputBuffer[0], m.inputBuffer[1:]
+		m.writeParameter(0, *pc, val)
+		*pc += 2
+	case OpOutput:
+		op1 := m.readParameter(0, *pc)
+		m.outputBuffer = append(m.outputBuffer, op1)
+		*pc += 2
+	default:
+		if !execStep(m, op, pc) {
+			return fmt.Errorf("Invalid operator '%d' at index %d", op, *pc)
+		}
+	}
+
+	if *pc >= m.memory.Len() {
+		return fmt.Errorf("Reached end of code")
+	}
+
+	return nil
+}
@@ -0,0 +1,59 @@
+package main
+
+import "testing"
+
+// TestContinueAdvancesPastBreakpoint guards against Continue getting stuck
+// re-triggering the breakpoint it just stopped at: a second Continue call
+// must step past the current pc and run to the next breakpoint instead of
+// making zero progress.
+func TestContinueAdvancesPastBreakpoint(t *testing.T) {
+	var m IntCodeMachine
+	m.Init([]int{1101, 2, 3, 7, 4, 7, 99, 0}, nil)
+
+	d := m.Debugger()
+	d.SetBreakpoint(0)
+	d.SetBreakpoint(4)
+
+	if err := d.Continue(); err != nil {
+		t.Fatalf("first Continue: %v", err)
+	}
+	if m.pc != 0 {
+		t.Fatalf("after first Continue, pc = %d, want 0", m.pc)
+	}
+
+	if err := d.Continue(); err != nil {
+		t.Fatalf("second Continue: %v", err)
+	}
+	if m.pc != 4 {
+		t.Fatalf("after second Continue, pc = %d, want 4 (Continue made no progress off the breakpoint it just stopped at)", m.pc)
+	}
+}
+
+// TestBacktracePushesAndPops guards against Backtrace being an unbounded log
+// of every relative-base change: two call-like increases followed by two
+// return-like decreases should grow the stack to depth 2 and then unwind it
+// back to empty, not leave all four changes sitting in the slice.
+func TestBacktracePushesAndPops(t *testing.T) {
+	var m IntCodeMachine
+	m.Init([]int{109, 5, 109, 5, 109, -5, 109, -5, 99}, nil)
+
+	d := m.Debugger()
+
+	for i := 0; i < 2; i++ {
+		if err := d.Step(); err != nil {
+			t.Fatalf("step %d: %v", i, err)
+		}
+	}
+	if depth := len(d.Backtrace()); depth != 2 {
+		t.Fatalf("after two pushes, backtrace depth = %d, want 2", depth)
+	}
+
+	for i := 0; i < 2; i++ {
+		if err := d.Step(); err != nil {
+			t.Fatalf("step %d: %v", i, err)
+		}
+	}
+	if depth := len(d.Backtrace()); depth != 0 {
+		t.Fatalf("after two matching pops, backtrace depth = %d, want 0 (frames should unwind, not just accumulate)", depth)
+	}
+}
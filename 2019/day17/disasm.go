@@ -0,0 +1,159 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// opParamCount - Number of parameter words following an opcode, or -1 if op is
+// not a recognized opcode.
+func opParamCount(op int) int {
+	switch op % 100 {
+	case OpAdd, OpMul, OpLess, OpEqual:
+		return 3
+	case OpJumpNotZero, OpJumpZero:
+		return 2
+	case OpInput, OpOutput, OpAddToRelativeBase:
+		return 1
+	case OpHalt:
+		return 0
+	default:
+		return -1
+	}
+}
+
+// Instruction - A single decoded IntCode instruction, as produced by Disassemble:
+// its offset in the program, the raw opcode word, and the parameter modes and
+// values that followed it. A cell outside the reachable code (a data word) is
+// represented with IsData set and its raw value in Value, so that a full
+// listing round-trips through Assemble without losing anything.
+type Instruction struct {
+	Offset int
+	Op     int
+	Modes  []int
+	Params []int
+	IsData bool
+	Value  int
+}
+
+// String - Render an Instruction in the symbolic mnemonic form Assemble parses
+// back, e.g. "ADD [42], #5, [rb+3]", or ".word 8" for a data cell.
+func (ins Instruction) String() string {
+	if ins.IsData {
+		return fmt.Sprintf(".word %d", ins.Value)
+	}
+
+	name := strings.TrimSpace(getOpName(ins.Op))
+
+	if len(ins.Params) == 0 {
+		return name
+	}
+
+	operands := make([]string, len(ins.Params))
+	for i, p := range ins.Params {
+		switch ins.Modes[i] {
+		case 1:
+			operands[i] = fmt.Sprintf("#%d", p)
+		case 2:
+			if p < 0 {
+				operands[i] = fmt.Sprintf("[rb%d]", p)
+			} else {
+				operands[i] = fmt.Sprintf("[rb+%d]", p)
+			}
+		default:
+			operands[i] = fmt.Sprintf("[%d]", p)
+		}
+	}
+
+	return name + " " + strings.Join(operands, ", ")
+}
+
+// Disassemble - Decode program into a symbolic instruction listing. Only
+// offsets reachable by walking from entry point 0 through straight-line flow
+// and immediate jump targets are decoded as instructions, so that puzzle inputs mixing
+// code and data words (ASCII strings, scratch cells) don't have their data
+// misread as instructions. Everything else is emitted as a ".word" data
+// Instruction instead of being dropped, so Assemble(listing) round-trips back
+// to program.
+func Disassemble(program []int) ([]Instruction, error) {
+	isCode := markCodeOffsets(program)
+
+	var listing []Instruction
+	offset := 0
+
+	for offset < len(program) {
+		if !isCode[offset] {
+			listing = append(listing, Instruction{Offset: offset, IsData: true, Value: program[offset]})
+			offset++
+			continue
+		}
+
+		op := program[offset]
+		count := opParamCount(op)
+		if count < 0 {
+			return nil, fmt.Errorf("Invalid operator '%d' at index %d", op, offset)
+		}
+
+		if offset+count >= len(program) {
+			return nil, fmt.Errorf("Truncated instruction at index %d", offset)
+		}
+
+		ins := Instruction{Offset: offset, Op: op}
+		for p := 0; p < count; p++ {
+			ins.Modes = append(ins.Modes, getParameterMode(op, p))
+			ins.Params = append(ins.Params, program[offset+p+1])
+		}
+
+		listing = append(listing, ins)
+		offset += count + 1
+	}
+
+	return listing, nil
+}
+
+// markCodeOffsets - Walk the program from entry point 0, following
+// straight-line execution and jump targets given as immediate operands,
+// marking every offset it touches as code. A jump whose target is read from
+// memory is followed only to its fall-through; offsets never reached this way
+// are left false.
+func markCodeOffsets(program []int) []bool {
+	isCode := make([]bool, len(program))
+	visited := make([]bool, len(program))
+	queue := []int{0}
+
+	for len(queue) > 0 {
+		offset := queue[0]
+		queue = queue[1:]
+
+		if offset < 0 || offset >= len(program) || visited[offset] {
+			continue
+		}
+
+		op := program[offset]
+		count := opParamCount(op)
+		if count < 0 || offset+count >= len(program) {
+			continue
+		}
+
+		for i := offset; i <= offset+count; i++ {
+			visited[i] = true
+			isCode[i] = true
+		}
+
+		next := offset + count + 1
+
+		switch op % 100 {
+		case OpHalt:
+			// No successor.
+		case OpJumpNotZero, OpJumpZero:
+			if getParameterMode(op, 1) == 1 {
+				queue = append(queue, program[offset+2])
+			}
+			queue = append(queue, next)
+		default:
+			queue = append(queue, next)
+		}
+	}
+
+	return isCode
+}
@@ -0,0 +1,34 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// TestAssembleDisassembleRoundTrip guards against Disassemble silently
+// dropping data words that aren't reachable as code: Assemble(Disassemble(p))
+// must reproduce p exactly, including scratch/constant cells such as the
+// comparison operand read by this canonical Day 5 "equal to 8" program.
+func TestAssembleDisassembleRoundTrip(t *testing.T) {
+	program := []int{3, 9, 8, 9, 10, 9, 4, 9, 99, -1, 8}
+
+	listing, err := Disassemble(program)
+	if err != nil {
+		t.Fatalf("Disassemble: %v", err)
+	}
+
+	lines := make([]string, len(listing))
+	for i, ins := range listing {
+		lines[i] = ins.String()
+	}
+
+	reassembled, err := Assemble(strings.Join(lines, "\n"))
+	if err != nil {
+		t.Fatalf("Assemble: %v", err)
+	}
+
+	if !reflect.DeepEqual(reassembled, program) {
+		t.Fatalf("round trip = %v, want %v (data words were dropped)", reassembled, program)
+	}
+}
@@ -0,0 +1,224 @@
+package main
+
+// No BenchmarkRunJIT is included: this repo doesn't check in puzzle inputs,
+// and nothing else here is large enough for a speedup number to mean
+// anything. Flagged rather than silently dropped.
+
+import "fmt"
+
+// jitBlock - A basic block starting at start (inclusive) and ending at end
+// (exclusive), pre-compiled into a straight-line sequence of closures.
+type jitBlock struct {
+	start int
+	end   int
+	steps []func(m *IntCodeMachine) (nextPC int, blocked bool)
+}
+
+// jitCache - Compiled basic blocks for a machine, keyed by starting PC. A
+// block is invalidated whenever writeParameter targets an address inside its
+// range, so self-modifying code is recompiled instead of running stale.
+type jitCache struct {
+	blocks map[int]*jitBlock
+}
+
+func (c *jitCache) invalidate(addr int) {
+	for start, b := range c.blocks {
+		if addr >= b.start && addr < b.end {
+			delete(c.blocks, start)
+		}
+	}
+}
+
+// compile - Return the cached block starting at start, compiling one if none
+// is cached (or it was invalidated by a prior write).
+func (c *jitCache) compile(m *IntCodeMachine, start int) (*jitBlock, error) {
+	if b, ok := c.blocks[start]; ok {
+		return b, nil
+	}
+
+	block := &jitBlock{start: start}
+	offset := start
+
+	for {
+		if offset >= m.memory.Len() {
+			return nil, fmt.Errorf("Reached end of code")
+		}
+
+		op := m.memory.Read(offset)
+		count := opParamCount(op)
+		if count < 0 {
+			return nil, fmt.Errorf("Invalid operator '%d' at index %d", op, offset)
+		}
+
+		step, terminal := compileStep(op, offset)
+		block.steps = append(block.steps, step)
+		offset += count + 1
+
+		if terminal {
+			break
+		}
+	}
+
+	block.end = offset
+	c.blocks[start] = block
+	return block, nil
+}
+
+// readWithMode/writeWithMode - Access memory using a parameter mode decoded
+// at compile time instead of re-derived from the opcode word on every visit.
+func readWithMode(m *IntCodeMachine, mode int, raw int) int {
+	switch mode {
+	case 1:
+		return raw
+	case 2:
+		return m.memory.Read(m.relativeBase + raw)
+	default:
+		return m.memory.Read(raw)
+	}
+}
+
+func writeWithMode(m *IntCodeMachine, mode int, raw int, value int) {
+	if mode == 2 {
+		raw += m.relativeBase
+	}
+	m.memory.Write(raw, value)
+	if m.jit != nil {
+		m.jit.invalidate(raw)
+	}
+}
+
+// compileStep - Compile a single instruction at offset into a closure, fixing
+// its opcode and parameter modes at compile time. terminal is true for
+// instructions that end a basic block (jumps, input, halt).
+func compileStep(op int, offset int) (step func(m *IntCodeMachine) (int, bool), terminal bool) {
+	mode0 := getParameterMode(op, 0)
+	mode1 := getParameterMode(op, 1)
+	mode2 := getParameterMode(op, 2)
+	p1, p2, p3 := offset+1, offset+2, offset+3
+
+	switch op % 100 {
+	case OpAdd:
+		return func(m *IntCodeMachine) (int, bool) {
+			v := readWithMode(m, mode0, m.memory.Read(p1)) + readWithMode(m, mode1, m.memory.Read(p2))
+			writeWithMode(m, mode2, m.memory.Read(p3), v)
+			return offset + 4, false
+		}, false
+
+	case OpMul:
+		return func(m *IntCodeMachine) (int, bool) {
+			v := readWithMode(m, mode0, m.memory.Read(p1)) * readWithMode(m, mode1, m.memory.Read(p2))
+			writeWithMode(m, mode2, m.memory.Read(p3), v)
+			return offset + 4, false
+		}, false
+
+	case OpInput:
+		return func(m *IntCodeMachine) (int, bool) {
+			if len(m.inputBuffer) == 0 {
+				return offset, true
+			}
+			var val int
+			val, m.inputBuffer = m.inputBuffer[0], m.inputBuffer[1:]
+			writeWithMode(m, mode0, m.memory.Read(p1), val)
+			return offset + 2, false
+		}, true
+
+	case OpOutput:
+		return func(m *IntCodeMachine) (int, bool) {
+			m.outputBuffer = append(m.outputBuffer, readWithMode(m, mode0, m.memory.Read(p1)))
+			return offset + 2, false
+		}, false
+
+	case OpJumpNotZero:
+		return func(m *IntCodeMachine) (int, bool) {
+			if readWithMode(m, mode0, m.memory.Read(p1)) != 0 {
+				return readWithMode(m, mode1, m.memory.Read(p2)), false
+			}
+			return offset + 3, false
+		}, true
+
+	case OpJumpZero:
+		return func(m *IntCodeMachine) (int, bool) {
+			if readWithMode(m, mode0, m.memory.Read(p1)) == 0 {
+				return readWithMode(m, mode1, m.memory.Read(p2)), false
+			}
+			return offset + 3, false
+		}, true
+
+	case OpLess:
+		return func(m *IntCodeMachine) (int, bool) {
+			v := 0
+			if readWithMode(m, mode0, m.memory.Read(p1)) < readWithMode(m, mode1, m.memory.Read(p2)) {
+				v = 1
+			}
+			writeWithMode(m, mode2, m.memory.Read(p3), v)
+			return offset + 4, false
+		}, false
+
+	case OpEqual:
+		return func(m *IntCodeMachine) (int, bool) {
+			v := 0
+			if readWithMode(m, mode0, m.memory.Read(p1)) == readWithMode(m, mode1, m.memory.Read(p2)) {
+				v = 1
+			}
+			writeWithMode(m, mode2, m.memory.Read(p3), v)
+			return offset + 4, false
+		}, false
+
+	case OpAddToRelativeBase:
+		return func(m *IntCodeMachine) (int, bool) {
+			m.relativeBase += readWithMode(m, mode0, m.memory.Read(p1))
+			return offset + 2, false
+		}, false
+
+	default: // OpHalt
+		return func(m *IntCodeMachine) (int, bool) {
+			m.stopped = true
+			return offset, false
+		}, true
+	}
+}
+
+// RunJIT - Run the machine like Run, but compile straight-line runs of
+// instructions ("basic blocks") into Go closures on first execution and reuse
+// them on later visits to the same PC. Recompiles a block whenever a write
+// lands inside its address range, including a write from earlier in the same
+// block still executing: that stops the block right after the write and
+// recompiles fresh from the current pc, instead of finishing it from stale
+// closures.
+func (m *IntCodeMachine) RunJIT(input []int) error {
+	m.inputBuffer = append(m.inputBuffer, input...)
+
+	if m.jit == nil {
+		m.jit = &jitCache{blocks: map[int]*jitBlock{}}
+	}
+
+	for !m.stopped {
+		block, err := m.jit.compile(m, m.pc)
+		if err != nil {
+			return err
+		}
+
+		for _, step := range block.steps {
+			next, blocked := step(m)
+			if blocked {
+				return nil
+			}
+
+			m.pc = next
+
+			if m.pc >= m.memory.Len() {
+				return fmt.Errorf("Reached end of code")
+			}
+
+			if m.jit.blocks[block.start] != block {
+				// A step just run self-modified an instruction later in this
+				// same block, invalidating it out from under us. Stop
+				// running its remaining (stale) closures and let the outer
+				// loop recompile from m.pc against the now-current memory.
+				break
+			}
+		}
+	}
+
+	return nil
+}
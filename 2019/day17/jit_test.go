@@ -0,0 +1,90 @@
+package main
+
+import "testing"
+
+// TestInitClearsJITCache guards against a stale compiled block from a
+// previous program surviving a re-Init, which would otherwise let RunJIT
+// silently execute code from a program that's no longer loaded.
+func TestInitClearsJITCache(t *testing.T) {
+	var m IntCodeMachine
+
+	progAdd := []int{1101, 2, 3, 7, 4, 7, 99, 0}
+	m.Init(progAdd, nil)
+	if err := m.RunJIT(nil); err != nil {
+		t.Fatalf("RunJIT(add): %v", err)
+	}
+	if out := m.ReadOutput(); len(out) != 1 || out[0] != 5 {
+		t.Fatalf("RunJIT(add) = %v, want [5]", out)
+	}
+
+	progMul := []int{1102, 2, 3, 7, 4, 7, 99, 0}
+	m.Init(progMul, nil)
+	if err := m.RunJIT(nil); err != nil {
+		t.Fatalf("RunJIT(mul): %v", err)
+	}
+	if out := m.ReadOutput(); len(out) != 1 || out[0] != 6 {
+		t.Fatalf("RunJIT(mul) = %v, want [6] (a stale JIT block from the previous Init was reused)", out)
+	}
+}
+
+// TestRestoreClearsJITCache guards against a compiled block surviving a
+// Restore, which would defeat the point of rewinding past self-modifying code.
+func TestRestoreClearsJITCache(t *testing.T) {
+	var m IntCodeMachine
+
+	prog := []int{1101, 2, 3, 7, 4, 7, 99, 0}
+	m.Init(prog, nil)
+	snap := m.Snapshot()
+
+	if err := m.RunJIT(nil); err != nil {
+		t.Fatalf("RunJIT: %v", err)
+	}
+	m.ReadOutput()
+
+	m.Restore(snap)
+	m.memory.Write(0, 1102) // ADD -> MUL, as if rewound before a self-modification
+
+	if err := m.RunJIT(nil); err != nil {
+		t.Fatalf("RunJIT after restore: %v", err)
+	}
+	if out := m.ReadOutput(); len(out) != 1 || out[0] != 6 {
+		t.Fatalf("RunJIT after restore = %v, want [6] (a stale JIT block survived Restore)", out)
+	}
+}
+
+// TestRunJITSameBlockSelfModification guards against a block compiled with a
+// stale opcode for one of its own later instructions: the ADD at offset 0
+// overwrites the opcode word of the instruction at offset 4 (from ADD to MUL)
+// before offset 4 executes, and both belong to the same basic block (no jump
+// between them). RunJIT must match Run's result instead of running offset 4
+// from the pre-modification closure it was compiled with.
+func TestRunJITSameBlockSelfModification(t *testing.T) {
+	prog := []int{
+		1, 9, 10, 4, // 0: ADD mem[9]+mem[10] -> mem[4]   (overwrites the opcode at 4)
+		1, 11, 12, 13, // 4: ADD mem[11]+mem[12] -> mem[13] (opcode rewritten to MUL before this runs)
+		99, // 8: HALT
+		1,  // 9
+		1,  // 10: mem[9]+mem[10] = 2 (MUL's opcode), written into mem[4]
+		3,  // 11
+		4,  // 12
+		0,  // 13: result
+	}
+
+	var want IntCodeMachine
+	want.Init(append([]int(nil), prog...), nil)
+	if err := want.Run(nil); err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	wantResult := want.memory.Read(13)
+
+	var got IntCodeMachine
+	got.Init(append([]int(nil), prog...), nil)
+	if err := got.RunJIT(nil); err != nil {
+		t.Fatalf("RunJIT: %v", err)
+	}
+	gotResult := got.memory.Read(13)
+
+	if gotResult != wantResult {
+		t.Fatalf("RunJIT result = %d, want %d (same as Run); RunJIT ran the self-modified instruction from a stale closure", gotResult, wantResult)
+	}
+}
@@ -0,0 +1,149 @@
+package main
+
+// No BenchmarkPageMemoryClone is included, for the same reason jit.go skips
+// one for RunJIT: this repo doesn't check in puzzle inputs large enough for
+// the savings to show. Flagged rather than silently dropped.
+
+import "fmt"
+
+const pageSize = 512 // ints per page (4 KiB at 8 bytes/int)
+
+// Memory - Backing store for an IntCodeMachine's address space, letting Clone
+// hand out machines that share pages copy-on-write instead of paying for a
+// full copy per fork.
+type Memory interface {
+	Read(addr int) int
+	Write(addr int, value int)
+	Len() int
+	Snapshot() []int
+	Clone() Memory
+}
+
+// sliceMemory - The original dense backend: one flat slice, grown up front to
+// minMemorySize. Cheap to read and write, but a Clone (or copy(memory, ...))
+// always costs a full copy.
+type sliceMemory struct {
+	data []int
+}
+
+func newSliceMemory(program []int) *sliceMemory {
+	data := make([]int, Max(len(program), minMemorySize))
+	copy(data, program)
+	return &sliceMemory{data: data}
+}
+
+func (s *sliceMemory) Read(addr int) int         { return s.data[addr] }
+func (s *sliceMemory) Write(addr int, value int) { s.data[addr] = value }
+func (s *sliceMemory) Len() int                  { return len(s.data) }
+
+func (s *sliceMemory) Snapshot() []int {
+	out := make([]int, len(s.data))
+	copy(out, s.data)
+	return out
+}
+
+func (s *sliceMemory) Clone() Memory {
+	return newSliceMemory(s.data)
+}
+
+// page - One pageSize-int chunk of a pageMemory's address space.
+type page struct {
+	data [pageSize]int
+}
+
+// pageMemory - A sparse backend that allocates pages lazily on first write and
+// shares them copy-on-write across Clone calls. See InitPaged for why this is
+// worth the extra bookkeeping over sliceMemory.
+type pageMemory struct {
+	pages map[int]*page
+	owned map[int]bool
+	size  int
+}
+
+func newPageMemory(program []int) *pageMemory {
+	m := &pageMemory{
+		pages: map[int]*page{},
+		owned: map[int]bool{},
+		size:  Max(len(program), minMemorySize),
+	}
+
+	for addr, v := range program {
+		if v != 0 {
+			m.Write(addr, v)
+		}
+	}
+
+	return m
+}
+
+// pageFor - Return the page containing addr, allocating it (or copying it off
+// a shared clone) on first touch when forWrite is set.
+func (m *pageMemory) pageFor(addr int, forWrite bool) *page {
+	idx := addr / pageSize
+
+	p, ok := m.pages[idx]
+	if !ok {
+		if !forWrite {
+			return nil
+		}
+		p = &page{}
+		m.pages[idx] = p
+		m.owned[idx] = true
+		return p
+	}
+
+	if forWrite && !m.owned[idx] {
+		copied := *p
+		p = &copied
+		m.pages[idx] = p
+		m.owned[idx] = true
+	}
+
+	return p
+}
+
+func (m *pageMemory) Read(addr int) int {
+	p := m.pageFor(addr, false)
+	if p == nil {
+		return 0
+	}
+	return p.data[addr%pageSize]
+}
+
+func (m *pageMemory) Write(addr int, value int) {
+	if addr < 0 || addr >= m.size {
+		panic(fmt.Sprintf("pageMemory.Write: index out of range [%d] with length %d", addr, m.size))
+	}
+	p := m.pageFor(addr, true)
+	p.data[addr%pageSize] = value
+}
+
+func (m *pageMemory) Len() int { return m.size }
+
+func (m *pageMemory) Snapshot() []int {
+	out := make([]int, m.size)
+	for idx, p := range m.pages {
+		base := idx * pageSize
+		for i, v := range p.data {
+			if base+i < m.size {
+				out[base+i] = v
+			}
+		}
+	}
+	return out
+}
+
+// Clone - Produce an independent pageMemory sharing pages with m until either
+// side writes one.
+func (m *pageMemory) Clone() Memory {
+	pages := make(map[int]*page, len(m.pages))
+	owned := make(map[int]bool, len(m.pages))
+
+	for idx, p := range m.pages {
+		pages[idx] = p
+		owned[idx] = false
+		m.owned[idx] = false
+	}
+
+	return &pageMemory{pages: pages, owned: owned, size: m.size}
+}
@@ -0,0 +1,38 @@
+package main
+
+import "testing"
+
+// TestPageMemoryCloneIsolation guards the copy-on-write contract Clone
+// promises: writing through either the original or the clone after Clone must
+// not be visible through the other.
+func TestPageMemoryCloneIsolation(t *testing.T) {
+	orig := newPageMemory([]int{1, 2, 3})
+
+	clone := orig.Clone()
+
+	orig.Write(0, 100)
+	if got := clone.Read(0); got != 1 {
+		t.Fatalf("write through orig visible in clone: clone.Read(0) = %d, want 1", got)
+	}
+
+	clone.Write(1, 200)
+	if got := orig.Read(1); got != 2 {
+		t.Fatalf("write through clone visible in orig: orig.Read(1) = %d, want 2", got)
+	}
+}
+
+// TestPageMemoryWriteOutOfRangePanics guards pageMemory.Write against
+// silently growing past its allocated size, the way sliceMemory.Write panics
+// on an out-of-bounds index instead of growing: the two Memory
+// implementations must fail the same way for the same out-of-bounds write.
+func TestPageMemoryWriteOutOfRangePanics(t *testing.T) {
+	m := newPageMemory([]int{1, 2, 3})
+
+	defer func() {
+		if recover() == nil {
+			t.Fatal("Write(m.Len(), ...) did not panic")
+		}
+	}()
+
+	m.Write(m.Len(), 1)
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"encoding/json"
+	"io/ioutil"
+)
+
+// Snapshot - A deep copy of an IntCodeMachine's state. Useful for checkpointing
+// before an irreversible choice (Day 25's text adventure, Day 15's oxygen-system
+// maze) and rewinding with Restore, or for persisting a machine to disk between
+// runs with SaveState/LoadState.
+type Snapshot struct {
+	PC           int   `json:"pc"`
+	RelativeBase int   `json:"relativeBase"`
+	Memory       []int `json:"memory"`
+	InputBuffer  []int `json:"inputBuffer"`
+	OutputBuffer []int `json:"outputBuffer"`
+	Stopped      bool  `json:"stopped"`
+}
+
+// Snapshot - Capture a deep copy of the machine's current state.
+func (m *IntCodeMachine) Snapshot() Snapshot {
+	memory := m.memory.Snapshot()
+
+	inputBuffer := make([]int, len(m.inputBuffer))
+	copy(inputBuffer, m.inputBuffer)
+
+	outputBuffer := make([]int, len(m.outputBuffer))
+	copy(outputBuffer, m.outputBuffer)
+
+	return Snapshot{
+		PC:           m.pc,
+		RelativeBase: m.relativeBase,
+		Memory:       memory,
+		InputBuffer:  inputBuffer,
+		OutputBuffer: outputBuffer,
+		Stopped:      m.stopped,
+	}
+}
+
+// Restore - Reset the machine to a previously captured Snapshot. The snapshot is
+// deep-copied in, so the same Snapshot can be restored from more than once.
+// Restore always rebuilds a dense sliceMemory, since Snapshot flattens memory
+// to a plain slice and doesn't record which Memory implementation produced it.
+// Also drops any compiled JIT blocks: they were compiled against the memory
+// this call is replacing, and a rewind past self-modifying code must not keep
+// running a block compiled after the point being rewound to.
+func (m *IntCodeMachine) Restore(s Snapshot) {
+	m.pc = s.PC
+	m.relativeBase = s.RelativeBase
+	m.stopped = s.Stopped
+	m.memory = newSliceMemory(s.Memory)
+	m.jit = nil
+
+	m.inputBuffer = make([]int, len(s.InputBuffer))
+	copy(m.inputBuffer, s.InputBuffer)
+
+	m.outputBuffer = make([]int, len(s.OutputBuffer))
+	copy(m.outputBuffer, s.OutputBuffer)
+}
+
+// SaveState - Serialize a snapshot of the machine to path as JSON.
+func (m *IntCodeMachine) SaveState(path string) error {
+	data, err := json.Marshal(m.Snapshot())
+	if err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(path, data, 0644)
+}
+
+// LoadState - Restore the machine from a JSON snapshot previously written by
+// SaveState.
+func (m *IntCodeMachine) LoadState(path string) error {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	var s Snapshot
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+
+	m.Restore(s)
+	return nil
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// TestSnapshotRestoreRoundTrip guards Restore against diverging from the
+// state Snapshot captured: running further, then restoring, must put the
+// machine back exactly where the snapshot was taken.
+func TestSnapshotRestoreRoundTrip(t *testing.T) {
+	var m IntCodeMachine
+	m.Init([]int{1101, 2, 3, 7, 4, 7, 99, 0}, nil) // ADD #2,#3 -> mem[7]; OUT mem[7]; HALT
+
+	snap := m.Snapshot()
+
+	if err := m.Run(nil); err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if out := m.ReadOutput(); len(out) != 1 || out[0] != 5 {
+		t.Fatalf("Run output = %v, want [5]", out)
+	}
+
+	m.Restore(snap)
+
+	if m.pc != 0 || m.stopped {
+		t.Fatalf("after Restore: pc = %d, stopped = %v, want 0, false", m.pc, m.stopped)
+	}
+	if err := m.Run(nil); err != nil {
+		t.Fatalf("Run after Restore: %v", err)
+	}
+	if out := m.ReadOutput(); len(out) != 1 || out[0] != 5 {
+		t.Fatalf("Run after Restore output = %v, want [5] (Restore didn't put the machine back)", out)
+	}
+}
+
+// TestSaveStateLoadStateRoundTrip guards the JSON persistence path: a machine
+// loaded from a file saved by SaveState must behave the same as the one that
+// saved it.
+func TestSaveStateLoadStateRoundTrip(t *testing.T) {
+	var m IntCodeMachine
+	m.Init([]int{1101, 2, 3, 7, 4, 7, 99, 0}, nil)
+
+	dir, err := ioutil.TempDir("", "intcode-snapshot")
+	if err != nil {
+		t.Fatalf("TempDir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "state.json")
+
+	if err := m.SaveState(path); err != nil {
+		t.Fatalf("SaveState: %v", err)
+	}
+
+	var loaded IntCodeMachine
+	loaded.Init(nil, nil)
+	if err := loaded.LoadState(path); err != nil {
+		t.Fatalf("LoadState: %v", err)
+	}
+
+	if !reflect.DeepEqual(loaded.Snapshot(), m.Snapshot()) {
+		t.Fatalf("LoadState produced a different snapshot than the one SaveState wrote")
+	}
+
+	if err := loaded.Run(nil); err != nil {
+		t.Fatalf("Run after LoadState: %v", err)
+	}
+	if out := loaded.ReadOutput(); len(out) != 1 || out[0] != 5 {
+		t.Fatalf("Run after LoadState output = %v, want [5]", out)
+	}
+}